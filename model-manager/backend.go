@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPullUnsupported is returned by Backend.Pull when the backend has no
+// runtime pull mechanism. Callers should degrade to presence-checking
+// only: keep polling List for the model to show up rather than treating
+// this as a fatal error.
+var ErrPullUnsupported = errors.New("backend does not support pulling models")
+
+// Backend abstracts the locally-hosted LLM runtime the supervisor talks
+// to, so the readiness gate isn't tied to Ollama specifically.
+type Backend interface {
+	// Ping reports whether the backend is reachable and responding.
+	Ping(ctx context.Context, timeout time.Duration) error
+	// List returns the set of model names the backend currently has
+	// loaded or available.
+	List(ctx context.Context, timeout time.Duration) (map[string]struct{}, error)
+	// Pull fetches model, reporting streaming progress via progress and
+	// recording attempts/failures/bytes via metrics. Returns
+	// ErrPullUnsupported if the backend can't pull models at runtime.
+	Pull(ctx context.Context, timeout time.Duration, model string, metrics *Metrics, progress *pullProgress) error
+	// Show resolves the manifest digest the backend currently has for
+	// model, for pinned-model verification.
+	Show(ctx context.Context, timeout time.Duration, model string) (digest string, err error)
+	// Warmup runs a minimal generation against model so the runtime
+	// mmaps weights and initializes before the first real request.
+	Warmup(ctx context.Context, timeout time.Duration, model string) error
+}
+
+// selectBackend constructs the Backend named by BACKEND (default
+// "ollama"), pointed at base.
+func selectBackend(name, base string) (Backend, error) {
+	switch name {
+	case "", "ollama":
+		return &ollamaBackend{base: base}, nil
+	case "llamacpp":
+		return &llamaCppBackend{base: base}, nil
+	case "vllm":
+		return &vllmBackend{base: base}, nil
+	default:
+		return nil, fmt.Errorf("unknown BACKEND %q (want ollama, llamacpp, or vllm)", name)
+	}
+}
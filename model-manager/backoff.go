@@ -0,0 +1,33 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// retryPolicy bounds how hard the supervisor will retry a failing
+// operation: at most maxAttempts tries, waiting a jittered exponential
+// backoff between attempts, capped at backoffCap.
+type retryPolicy struct {
+	maxAttempts int
+	backoffBase time.Duration
+	backoffCap  time.Duration
+}
+
+// jitteredBackoff returns a random delay in [0, min(cap, base*2^attempt)]
+// ("full jitter"), so a fleet of retrying clients doesn't sleep in lockstep.
+// attempt is zero-based: the delay before the first retry uses attempt 0.
+func jitteredBackoff(base, cap time.Duration, attempt int) time.Duration {
+	if base <= 0 || cap <= 0 {
+		return 0
+	}
+	if attempt < 0 {
+		attempt = 0
+	}
+	upper := float64(base) * math.Pow(2, float64(attempt))
+	if upper > float64(cap) || upper <= 0 {
+		upper = float64(cap)
+	}
+	return time.Duration(rand.Int63n(int64(upper) + 1))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitteredBackoff(t *testing.T) {
+	cases := []struct {
+		name    string
+		base    time.Duration
+		cap     time.Duration
+		attempt int
+		wantMax time.Duration
+	}{
+		{"first attempt bounded by base", time.Second, time.Minute, 0, time.Second},
+		{"grows with attempt", time.Second, time.Minute, 3, 8 * time.Second},
+		{"capped", time.Second, 5 * time.Second, 10, 5 * time.Second},
+		{"negative attempt treated as zero", time.Second, time.Minute, -1, time.Second},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got := jitteredBackoff(tc.base, tc.cap, tc.attempt)
+				if got < 0 || got > tc.wantMax {
+					t.Fatalf("jitteredBackoff(%s, %s, %d) = %s, want in [0, %s]", tc.base, tc.cap, tc.attempt, got, tc.wantMax)
+				}
+			}
+		})
+	}
+}
+
+func TestJitteredBackoffZeroInputs(t *testing.T) {
+	if got := jitteredBackoff(0, time.Minute, 0); got != 0 {
+		t.Errorf("base=0: got %s, want 0", got)
+	}
+	if got := jitteredBackoff(time.Second, 0, 0); got != 0 {
+		t.Errorf("cap=0: got %s, want 0", got)
+	}
+}
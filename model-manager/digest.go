@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// modelRef is a required model, optionally pinned to a manifest digest via
+// the `name@sha256:<digest>` syntax compatible with Ollama's manifest
+// digests. Digest is empty for unpinned entries, which keep the
+// name-only presence check.
+type modelRef struct {
+	Name   string
+	Digest string
+}
+
+func parseModelRef(entry string) (modelRef, error) {
+	name, pin, pinned := strings.Cut(entry, "@")
+	if !pinned {
+		return modelRef{Name: entry}, nil
+	}
+	if name == "" {
+		return modelRef{}, fmt.Errorf("entry %q: missing model name before @", entry)
+	}
+	if !strings.HasPrefix(pin, "sha256:") {
+		return modelRef{}, fmt.Errorf("entry %q: unsupported digest pin %q (want sha256:<hex>)", entry, pin)
+	}
+	return modelRef{Name: name, Digest: pin}, nil
+}
+
+// verifyDigests compares each pinned entry in required against
+// backend.Show and errors on the first mismatch; unpinned entries are
+// skipped.
+func verifyDigests(ctx context.Context, backend Backend, timeout time.Duration, required []modelRef) error {
+	for _, ref := range required {
+		if ref.Digest == "" {
+			continue
+		}
+		got, err := backend.Show(ctx, timeout, ref.Name)
+		if err != nil {
+			return fmt.Errorf("model %s: verify digest: %w", ref.Name, err)
+		}
+		if got != ref.Digest {
+			return fmt.Errorf("model %s: digest mismatch: pinned %s, resolved %s", ref.Name, ref.Digest, got)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestParseModelRef(t *testing.T) {
+	cases := []struct {
+		entry   string
+		want    modelRef
+		wantErr bool
+	}{
+		{"llama3:8b", modelRef{Name: "llama3:8b"}, false},
+		{"llama3:8b@sha256:abcd", modelRef{Name: "llama3:8b", Digest: "sha256:abcd"}, false},
+		{"@sha256:abcd", modelRef{}, true},
+		{"llama3:8b@md5:abcd", modelRef{}, true},
+	}
+	for _, tc := range cases {
+		got, err := parseModelRef(tc.entry)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseModelRef(%q) = %+v, nil; want error", tc.entry, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseModelRef(%q) = %v", tc.entry, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseModelRef(%q) = %+v, want %+v", tc.entry, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// llamaCppBackend talks to llama.cpp's `server` binary, which exposes a
+// liveness check at /health and an OpenAI-compatible model list at
+// /v1/models. It has no runtime pull mechanism: models are fixed at
+// process start via command-line flags.
+type llamaCppBackend struct {
+	base string
+}
+
+func (b *llamaCppBackend) Ping(ctx context.Context, timeout time.Duration) error {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, b.base+"/health", nil)
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("llamacpp /health status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *llamaCppBackend) List(ctx context.Context, timeout time.Duration) (map[string]struct{}, error) {
+	return listOpenAIModels(ctx, b.base, timeout)
+}
+
+func (b *llamaCppBackend) Pull(ctx context.Context, timeout time.Duration, model string, metrics *Metrics, progress *pullProgress) error {
+	return fmt.Errorf("llamacpp: %w", ErrPullUnsupported)
+}
+
+func (b *llamaCppBackend) Show(ctx context.Context, timeout time.Duration, model string) (string, error) {
+	return "", fmt.Errorf("llamacpp: digest verification not supported")
+}
+
+// Warmup issues a single-token completion so the server loads its
+// (already-configured at startup) model into memory.
+func (b *llamaCppBackend) Warmup(ctx context.Context, timeout time.Duration, model string) error {
+	body := strings.NewReader(`{"prompt":"ok","n_predict":1}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/completion", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
+		return fmt.Errorf("completion failed: status %d: %s", resp.StatusCode, string(respBody))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
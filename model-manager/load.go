@@ -1,36 +1,20 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 )
 
-type tagsResponse struct {
-	Models []struct {
-		Name string `json:"name"`
-	} `json:"models"`
-}
-
-type pullEvent struct {
-	Status    string `json:"status"`
-	Error     string `json:"error"`
-	Digest    string `json:"digest"`
-	Total     int64  `json:"total"`
-	Completed int64  `json:"completed"`
-}
-
 func requireEnv(key string) (string, error) {
 	v := strings.TrimSpace(os.Getenv(key))
 	if v == "" {
@@ -39,18 +23,31 @@ func requireEnv(key string) (string, error) {
 	return v, nil
 }
 
-func parseModels(raw string) ([]string, error) {
+func getenvDefault(key, def string) string {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func parseModels(raw string) ([]modelRef, error) {
 	raw = strings.TrimSpace(raw)
 	if raw == "" {
 		return nil, errors.New("REQUIRED_MODELS must not be empty")
 	}
 	fields := strings.Fields(strings.ReplaceAll(raw, ",", " "))
-	var out []string
+	var out []modelRef
 	for _, f := range fields {
 		f = strings.TrimSpace(f)
-		if f != "" {
-			out = append(out, f)
+		if f == "" {
+			continue
+		}
+		ref, err := parseModelRef(f)
+		if err != nil {
+			return nil, err
 		}
+		out = append(out, ref)
 	}
 	if len(out) == 0 {
 		return nil, errors.New("REQUIRED_MODELS parsed to zero entries")
@@ -66,6 +63,14 @@ func mustParseDuration(val, key string) (time.Duration, error) {
 	return d, nil
 }
 
+func mustParseInt(val, key string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s invalid integer %q: %w", key, val, err)
+	}
+	return n, nil
+}
+
 func httpClient(timeout time.Duration) *http.Client {
 	tr := &http.Transport{
 		DialContext:           (&net.Dialer{Timeout: timeout}).DialContext,
@@ -76,121 +81,111 @@ func httpClient(timeout time.Duration) *http.Client {
 	return &http.Client{Transport: tr, Timeout: timeout}
 }
 
-func pingOllama(ctx context.Context, base string, timeout time.Duration) error {
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"/api/tags", nil)
-	resp, err := httpClient(timeout).Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("ollama /api/tags status %d", resp.StatusCode)
-	}
-	return nil
-}
-
-func listModels(ctx context.Context, base string, timeout time.Duration) (map[string]struct{}, error) {
-	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"/api/tags", nil)
-	resp, err := httpClient(timeout).Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("status %d", resp.StatusCode)
-	}
-	var tr tagsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
-		return nil, err
-	}
-	have := make(map[string]struct{}, len(tr.Models))
-	for _, m := range tr.Models {
-		have[m.Name] = struct{}{}
-	}
-	return have, nil
-}
-
-func pullModel(ctx context.Context, base string, timeout time.Duration, model string) error {
-	body := strings.NewReader(`{"name":"` + model + `"}`)
-	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, base+"/api/pull", body)
-	req.Header.Set("Content-Type", "application/json")
-	resp, err := httpClient(timeout).Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode/100 != 2 {
-		b, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
-		return fmt.Errorf("pull %s failed: status %d: %s", model, resp.StatusCode, string(b))
-	}
-	dec := json.NewDecoder(bufio.NewReader(resp.Body))
-	for {
-		var ev pullEvent
-		if err := dec.Decode(&ev); err != nil {
-			if err == io.EOF {
-				break
-			}
-			return fmt.Errorf("pull %s: decode: %w", model, err)
+// pullModel retries backend.Pull up to retry.maxAttempts times, sleeping a
+// jittered exponential backoff between attempts so a single flaky model
+// doesn't block startup forever. If the backend doesn't support pulling
+// at all, it gives up immediately since retrying can't help.
+func pullModel(ctx context.Context, backend Backend, timeout time.Duration, model string, metrics *Metrics, retry retryPolicy, progress *pullProgress) error {
+	var lastErr error
+	for attempt := 1; attempt <= retry.maxAttempts; attempt++ {
+		lastErr = backend.Pull(ctx, timeout, model, metrics, progress)
+		if lastErr == nil {
+			return nil
 		}
-		if ev.Error != "" {
-			return fmt.Errorf("pull %s: %s", model, ev.Error)
+		if errors.Is(lastErr, ErrPullUnsupported) {
+			return lastErr
 		}
-		if ev.Status != "" {
-			log.Printf("pull %s: %s", model, ev.Status)
+		if attempt == retry.maxAttempts {
+			break
 		}
-		if ev.Status == "success" {
-			return nil
+		sleep := jitteredBackoff(retry.backoffBase, retry.backoffCap, attempt-1)
+		log.Printf("pull %s: attempt %d/%d failed: %v; retrying in %s", model, attempt, retry.maxAttempts, lastErr, sleep)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
 		}
 	}
-	return fmt.Errorf("pull %s: stream ended without success", model)
+	return fmt.Errorf("pull %s: giving up after %d attempts: %w", model, retry.maxAttempts, lastErr)
 }
 
-func ensureAll(ctx context.Context, base string, timeout time.Duration, required []string) error {
-	have, err := listModels(ctx, base, timeout)
+// ensureAll pulls every missing model, at most concurrency at a time, and
+// reports their combined progress through a shared pullProgress. A model
+// whose backend can't pull it surfaces as an error here too, so the
+// caller's retry loop keeps polling List instead of treating it as ready.
+func ensureAll(ctx context.Context, backend Backend, timeout time.Duration, required []modelRef, metrics *Metrics, retry retryPolicy, concurrency int) error {
+	have, err := backend.List(ctx, timeout)
 	if err != nil {
 		return err
 	}
-	var missing []string
+	var missing []modelRef
 	for _, need := range required {
-		if _, ok := have[need]; !ok {
+		_, present := have[need.Name]
+		metrics.SetModelPresent(need.Name, present)
+		if !present {
 			missing = append(missing, need)
 		}
 	}
-	for _, m := range missing {
-		log.Printf("pulling missing model: %s", m)
-		if err := pullModel(ctx, base, timeout, m); err != nil {
+	if len(missing) > 0 {
+		progress := newPullProgress()
+		stopReport := progress.report(ctx, 2*time.Second)
+
+		group, groupCtx := newBoundedGroup(ctx, concurrency)
+		for _, ref := range missing {
+			model := ref.Name
+			log.Printf("pulling missing model: %s", model)
+			group.Go(func() error {
+				defer progress.clear(model)
+				return pullModel(groupCtx, backend, timeout, model, metrics, retry, progress)
+			})
+		}
+		err := group.Wait()
+		stopReport()
+		if err != nil {
+			if errors.Is(err, ErrPullUnsupported) {
+				log.Printf("%v; waiting for external provisioning", err)
+			}
 			return err
 		}
 	}
-	return nil
+
+	return verifyDigests(ctx, backend, timeout, required)
 }
 
-func waitUntilReady(ctx context.Context, base string, reqTimeout, startupLimit time.Duration, required []string) error {
+func waitUntilReady(ctx context.Context, backend Backend, reqTimeout, startupLimit time.Duration, required []modelRef, metrics *Metrics, retry retryPolicy, concurrency int) error {
 	deadline := time.Now().Add(startupLimit)
 	var last error
-	for {
+	for attempt := 0; ; attempt++ {
 		if time.Now().After(deadline) {
 			if last == nil {
 				last = errors.New("startup timeout")
 			}
 			return last
 		}
-		if err := pingOllama(ctx, base, reqTimeout); err != nil {
-			last = fmt.Errorf("ollama not responding: %w", err)
-			time.Sleep(2 * time.Second)
-			continue
-		}
-		if err := ensureAll(ctx, base, reqTimeout, required); err != nil {
+		if err := backend.Ping(ctx, reqTimeout); err != nil {
+			last = fmt.Errorf("backend not responding: %w", err)
+		} else if err := ensureAll(ctx, backend, reqTimeout, required, metrics, retry, concurrency); err != nil {
 			last = fmt.Errorf("ensure models: %w", err)
-			time.Sleep(2 * time.Second)
-			continue
+		} else {
+			metrics.MarkReady()
+			return nil
+		}
+		sleep := jitteredBackoff(retry.backoffBase, retry.backoffCap, attempt)
+		log.Printf("not ready yet (attempt %d): %v; retrying in %s", attempt+1, last, sleep)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
 		}
-		return nil
 	}
 }
 
 func main() {
-	ollama, err := requireEnv("OLLAMA_BASE_URL")
+	baseURL, err := requireEnv("OLLAMA_BASE_URL")
+	if err != nil {
+		log.Fatal(err)
+	}
+	backend, err := selectBackend(getenvDefault("BACKEND", "ollama"), baseURL)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -218,12 +213,60 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	maxPullAttempts, err := mustParseInt(getenvDefault("MAX_PULL_ATTEMPTS", "5"), "MAX_PULL_ATTEMPTS")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if maxPullAttempts < 1 {
+		log.Fatalf("MAX_PULL_ATTEMPTS must be >= 1, got %d", maxPullAttempts)
+	}
+	backoffBase, err := mustParseDuration(getenvDefault("BACKOFF_BASE", "1s"), "BACKOFF_BASE")
+	if err != nil {
+		log.Fatal(err)
+	}
+	backoffCap, err := mustParseDuration(getenvDefault("BACKOFF_CAP", "60s"), "BACKOFF_CAP")
+	if err != nil {
+		log.Fatal(err)
+	}
+	retry := retryPolicy{maxAttempts: maxPullAttempts, backoffBase: backoffBase, backoffCap: backoffCap}
+
+	pullConcurrency, err := mustParseInt(getenvDefault("PULL_CONCURRENCY", "2"), "PULL_CONCURRENCY")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if pullConcurrency < 1 {
+		log.Fatalf("PULL_CONCURRENCY must be >= 1, got %d", pullConcurrency)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
-	if err := waitUntilReady(ctx, ollama, reqTimeout, startupLimit, required); err != nil {
+
+	metrics := NewMetrics()
+	metricsAddr := getenvDefault("METRICS_ADDR", ":9090")
+	srv := serveMetrics(metricsAddr, metrics)
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+	log.Printf("metrics listening on %s", metricsAddr)
+
+	if err := waitUntilReady(ctx, backend, reqTimeout, startupLimit, required, metrics, retry, pullConcurrency); err != nil {
 		log.Fatalf("startup failed: %v", err)
 	}
-	log.Printf("all required models present")
+
+	readyMsg := "all required models present"
+	if getenvDefault("WARMUP", "false") == "true" {
+		warmupTimeout, err := mustParseDuration(getenvDefault("WARMUP_TIMEOUT", "30s"), "WARMUP_TIMEOUT")
+		if err != nil {
+			log.Fatal(err)
+		}
+		latencies, err := warmupAll(ctx, backend, warmupTimeout, required, metrics)
+		if err != nil {
+			log.Fatalf("warmup failed: %v", err)
+		}
+		readyMsg = fmt.Sprintf("%s, warmup latencies: %s", readyMsg, formatLatencies(required, latencies))
+	}
+	log.Printf("%s", readyMsg)
 	<-ctx.Done()
 }
-
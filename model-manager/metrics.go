@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Metrics tracks the supervisor's operational counters and gauges and
+// renders them in Prometheus text exposition format for /metrics.
+type Metrics struct {
+	mu sync.Mutex
+
+	pullAttempts    map[string]int64
+	pullFailures    map[string]int64
+	bytesDownloaded map[string]int64
+	modelPresent    map[string]bool
+	warmupLatency   map[string]float64
+
+	startedAt      time.Time
+	ready          bool
+	timeToReadySec float64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		pullAttempts:    make(map[string]int64),
+		pullFailures:    make(map[string]int64),
+		bytesDownloaded: make(map[string]int64),
+		modelPresent:    make(map[string]bool),
+		warmupLatency:   make(map[string]float64),
+		startedAt:       time.Now(),
+	}
+}
+
+func (m *Metrics) IncPullAttempt(model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pullAttempts[model]++
+}
+
+func (m *Metrics) IncPullFailure(model string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pullFailures[model]++
+}
+
+func (m *Metrics) AddBytesDownloaded(model string, n int64) {
+	if n <= 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bytesDownloaded[model] += n
+}
+
+func (m *Metrics) SetModelPresent(model string, present bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.modelPresent[model] = present
+}
+
+func (m *Metrics) RecordWarmupLatency(model string, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.warmupLatency[model] = d.Seconds()
+}
+
+// MarkReady records time-to-ready the first time it is called. Later calls
+// are no-ops so the metric reflects the initial readiness, not any
+// subsequent re-checks.
+func (m *Metrics) MarkReady() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.ready {
+		return
+	}
+	m.ready = true
+	m.timeToReadySec = time.Since(m.startedAt).Seconds()
+}
+
+func (m *Metrics) IsReady() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ready
+}
+
+func (m *Metrics) modelNames() []string {
+	seen := make(map[string]struct{})
+	for _, set := range []map[string]int64{m.pullAttempts, m.pullFailures, m.bytesDownloaded} {
+		for k := range set {
+			seen[k] = struct{}{}
+		}
+	}
+	for k := range m.modelPresent {
+		seen[k] = struct{}{}
+	}
+	for k := range m.warmupLatency {
+		seen[k] = struct{}{}
+	}
+	names := make([]string, 0, len(seen))
+	for k := range seen {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// render writes the current metric values in Prometheus text exposition
+// format (https://prometheus.io/docs/instrumenting/exposition_formats/).
+func (m *Metrics) render(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := m.modelNames()
+
+	fmt.Fprintln(w, "# HELP model_manager_pull_attempts_total Total pull attempts per model.")
+	fmt.Fprintln(w, "# TYPE model_manager_pull_attempts_total counter")
+	for _, model := range names {
+		fmt.Fprintf(w, "model_manager_pull_attempts_total{model=%q} %d\n", model, m.pullAttempts[model])
+	}
+
+	fmt.Fprintln(w, "# HELP model_manager_pull_failures_total Total failed pull attempts per model.")
+	fmt.Fprintln(w, "# TYPE model_manager_pull_failures_total counter")
+	for _, model := range names {
+		fmt.Fprintf(w, "model_manager_pull_failures_total{model=%q} %d\n", model, m.pullFailures[model])
+	}
+
+	fmt.Fprintln(w, "# HELP model_manager_bytes_downloaded_total Bytes downloaded per model during pulls.")
+	fmt.Fprintln(w, "# TYPE model_manager_bytes_downloaded_total counter")
+	for _, model := range names {
+		fmt.Fprintf(w, "model_manager_bytes_downloaded_total{model=%q} %d\n", model, m.bytesDownloaded[model])
+	}
+
+	fmt.Fprintln(w, "# HELP model_manager_model_present Whether a required model is currently present (1) or not (0).")
+	fmt.Fprintln(w, "# TYPE model_manager_model_present gauge")
+	for _, model := range names {
+		present := 0
+		if m.modelPresent[model] {
+			present = 1
+		}
+		fmt.Fprintf(w, "model_manager_model_present{model=%q} %d\n", model, present)
+	}
+
+	if len(m.warmupLatency) > 0 {
+		fmt.Fprintln(w, "# HELP model_manager_warmup_latency_seconds Seconds the warmup generation took per model.")
+		fmt.Fprintln(w, "# TYPE model_manager_warmup_latency_seconds gauge")
+		for _, model := range names {
+			if lat, ok := m.warmupLatency[model]; ok {
+				fmt.Fprintf(w, "model_manager_warmup_latency_seconds{model=%q} %f\n", model, lat)
+			}
+		}
+	}
+
+	fmt.Fprintln(w, "# HELP model_manager_ready Whether the supervisor has reached its initial ready state.")
+	fmt.Fprintln(w, "# TYPE model_manager_ready gauge")
+	ready := 0
+	if m.ready {
+		ready = 1
+	}
+	fmt.Fprintf(w, "model_manager_ready %d\n", ready)
+
+	if m.ready {
+		fmt.Fprintln(w, "# HELP model_manager_time_to_ready_seconds Seconds from process start to initial readiness.")
+		fmt.Fprintln(w, "# TYPE model_manager_time_to_ready_seconds gauge")
+		fmt.Fprintf(w, "model_manager_time_to_ready_seconds %f\n", m.timeToReadySec)
+	}
+}
+
+// serveMetrics starts the /metrics, /healthz and /readyz endpoints in the
+// background and returns the underlying server so the caller can shut it
+// down on exit.
+func serveMetrics(addr string, m *Metrics) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		m.render(w)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok\n")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if m.IsReady() {
+			io.WriteString(w, "ready\n")
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+		io.WriteString(w, "not ready\n")
+	})
+
+	srv := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("metrics server: %v", err)
+		}
+	}()
+	return srv
+}
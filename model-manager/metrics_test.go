@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMetricsRender(t *testing.T) {
+	m := NewMetrics()
+	m.IncPullAttempt("modelA")
+	m.IncPullAttempt("modelA")
+	m.IncPullFailure("modelA")
+	m.AddBytesDownloaded("modelA", 1024)
+	m.SetModelPresent("modelA", true)
+
+	var sb strings.Builder
+	m.render(&sb)
+	out := sb.String()
+
+	for _, want := range []string{
+		`model_manager_pull_attempts_total{model="modelA"} 2`,
+		`model_manager_pull_failures_total{model="modelA"} 1`,
+		`model_manager_bytes_downloaded_total{model="modelA"} 1024`,
+		`model_manager_model_present{model="modelA"} 1`,
+		`model_manager_ready 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("render() missing %q in:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "model_manager_time_to_ready_seconds") {
+		t.Errorf("render() should omit time_to_ready before MarkReady:\n%s", out)
+	}
+	if strings.Contains(out, "model_manager_warmup_latency_seconds") {
+		t.Errorf("render() should omit warmup latency when none recorded:\n%s", out)
+	}
+}
+
+func TestMetricsRenderReadyAndWarmup(t *testing.T) {
+	m := NewMetrics()
+	m.RecordWarmupLatency("modelA", 0)
+	m.MarkReady()
+
+	var sb strings.Builder
+	m.render(&sb)
+	out := sb.String()
+
+	if !strings.Contains(out, "model_manager_ready 1") {
+		t.Errorf("render() missing ready=1 after MarkReady:\n%s", out)
+	}
+	if !strings.Contains(out, "model_manager_time_to_ready_seconds") {
+		t.Errorf("render() missing time_to_ready after MarkReady:\n%s", out)
+	}
+	if !strings.Contains(out, `model_manager_warmup_latency_seconds{model="modelA"}`) {
+		t.Errorf("render() missing warmup latency:\n%s", out)
+	}
+}
+
+func TestServeMetricsHealthz(t *testing.T) {
+	m := NewMetrics()
+	srv := serveMetrics("127.0.0.1:0", m)
+	defer srv.Close()
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/healthz status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestServeMetricsReadyz(t *testing.T) {
+	m := NewMetrics()
+	srv := serveMetrics("127.0.0.1:0", m)
+	defer srv.Close()
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status before ready = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+	}
+
+	m.MarkReady()
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/readyz status after ready = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
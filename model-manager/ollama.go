@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ollamaBackend talks to an Ollama daemon's REST API.
+type ollamaBackend struct {
+	base string
+}
+
+type tagsResponse struct {
+	Models []struct {
+		Name string `json:"name"`
+	} `json:"models"`
+}
+
+type pullEvent struct {
+	Status    string `json:"status"`
+	Error     string `json:"error"`
+	Digest    string `json:"digest"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+}
+
+type showResponse struct {
+	Digest string `json:"digest"`
+}
+
+func (b *ollamaBackend) Ping(ctx context.Context, timeout time.Duration) error {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, b.base+"/api/tags", nil)
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("ollama /api/tags status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *ollamaBackend) List(ctx context.Context, timeout time.Duration) (map[string]struct{}, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, b.base+"/api/tags", nil)
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	var tr tagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tr); err != nil {
+		return nil, err
+	}
+	have := make(map[string]struct{}, len(tr.Models))
+	for _, m := range tr.Models {
+		have[m.Name] = struct{}{}
+	}
+	return have, nil
+}
+
+func (b *ollamaBackend) Pull(ctx context.Context, timeout time.Duration, model string, metrics *Metrics, progress *pullProgress) error {
+	metrics.IncPullAttempt(model)
+	body := strings.NewReader(`{"name":"` + model + `"}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/api/pull", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		metrics.IncPullFailure(model)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
+		metrics.IncPullFailure(model)
+		return fmt.Errorf("pull %s failed: status %d: %s", model, resp.StatusCode, string(respBody))
+	}
+	dec := json.NewDecoder(bufio.NewReader(resp.Body))
+	var lastCompleted int64
+	for {
+		var ev pullEvent
+		if err := dec.Decode(&ev); err != nil {
+			if err == io.EOF {
+				break
+			}
+			metrics.IncPullFailure(model)
+			return fmt.Errorf("pull %s: decode: %w", model, err)
+		}
+		if ev.Error != "" {
+			metrics.IncPullFailure(model)
+			return fmt.Errorf("pull %s: %s", model, ev.Error)
+		}
+		if ev.Completed > lastCompleted {
+			metrics.AddBytesDownloaded(model, ev.Completed-lastCompleted)
+			lastCompleted = ev.Completed
+		}
+		progress.update(model, ev)
+		if ev.Status == "success" {
+			metrics.SetModelPresent(model, true)
+			return nil
+		}
+	}
+	metrics.IncPullFailure(model)
+	return fmt.Errorf("pull %s: stream ended without success", model)
+}
+
+func (b *ollamaBackend) Show(ctx context.Context, timeout time.Duration, model string) (string, error) {
+	body := strings.NewReader(`{"name":"` + model + `"}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/api/show", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
+		return "", fmt.Errorf("show %s failed: status %d: %s", model, resp.StatusCode, string(respBody))
+	}
+	var sr showResponse
+	if err := json.NewDecoder(resp.Body).Decode(&sr); err != nil {
+		return "", fmt.Errorf("show %s: decode: %w", model, err)
+	}
+	if sr.Digest == "" {
+		return "", fmt.Errorf("show %s: response missing digest", model)
+	}
+	return sr.Digest, nil
+}
+
+type generateRequest struct {
+	Model   string          `json:"model"`
+	Prompt  string          `json:"prompt"`
+	Stream  bool            `json:"stream"`
+	Options generateOptions `json:"options"`
+}
+
+type generateOptions struct {
+	NumPredict int `json:"num_predict"`
+}
+
+// Warmup forces Ollama to mmap model's weights and initialize its runner
+// by issuing a single-token, non-streaming generation.
+func (b *ollamaBackend) Warmup(ctx context.Context, timeout time.Duration, model string) error {
+	reqBody, err := json.Marshal(generateRequest{
+		Model:   model,
+		Prompt:  "ok",
+		Stream:  false,
+		Options: generateOptions{NumPredict: 1},
+	})
+	if err != nil {
+		return err
+	}
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/api/generate", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
+		return fmt.Errorf("generate %s failed: status %d: %s", model, resp.StatusCode, string(respBody))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
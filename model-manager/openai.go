@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// openAIModelsResponse is the shape shared by every OpenAI-compatible
+// /v1/models endpoint (llama.cpp's server, vLLM).
+type openAIModelsResponse struct {
+	Data []struct {
+		ID string `json:"id"`
+	} `json:"data"`
+}
+
+// listOpenAIModels fetches base+"/v1/models" and returns the set of model
+// IDs it reports.
+func listOpenAIModels(ctx context.Context, base string, timeout time.Duration) (map[string]struct{}, error) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, base+"/v1/models", nil)
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("/v1/models status %d", resp.StatusCode)
+	}
+	var mr openAIModelsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
+		return nil, err
+	}
+	have := make(map[string]struct{}, len(mr.Data))
+	for _, m := range mr.Data {
+		have[m.ID] = struct{}{}
+	}
+	return have, nil
+}
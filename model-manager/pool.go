@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// boundedGroup runs tasks concurrently with a bound on how many run at
+// once, and collects the first error, in the spirit of
+// golang.org/x/sync/errgroup but implemented with only the standard
+// library since this program has no external dependencies.
+type boundedGroup struct {
+	sem    chan struct{}
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+	cancel context.CancelFunc
+}
+
+// newBoundedGroup returns a group along with a context derived from ctx
+// that is canceled as soon as any task passed to Go returns an error.
+func newBoundedGroup(ctx context.Context, concurrency int) (*boundedGroup, context.Context) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	groupCtx, cancel := context.WithCancel(ctx)
+	return &boundedGroup{sem: make(chan struct{}, concurrency), cancel: cancel}, groupCtx
+}
+
+// Go runs fn in its own goroutine, blocking until a concurrency slot is
+// free. The first non-nil error returned by any fn cancels the group's
+// context and is returned from Wait.
+func (g *boundedGroup) Go(fn func() error) {
+	g.sem <- struct{}{}
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() { <-g.sem }()
+		if err := fn(); err != nil {
+			g.mu.Lock()
+			if g.err == nil {
+				g.err = err
+				g.cancel()
+			}
+			g.mu.Unlock()
+		}
+	}()
+}
+
+// Wait blocks until every task started with Go has returned, then reports
+// the first error, if any.
+func (g *boundedGroup) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestBoundedGroupRunsAllTasks(t *testing.T) {
+	group, _ := newBoundedGroup(context.Background(), 2)
+	var ran int32
+	for i := 0; i < 5; i++ {
+		group.Go(func() error {
+			atomic.AddInt32(&ran, 1)
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		t.Fatalf("Wait() = %v, want nil", err)
+	}
+	if ran != 5 {
+		t.Fatalf("ran %d tasks, want 5", ran)
+	}
+}
+
+func TestBoundedGroupReturnsFirstError(t *testing.T) {
+	wantErr := errors.New("boom")
+	group, _ := newBoundedGroup(context.Background(), 3)
+	group.Go(func() error { return nil })
+	group.Go(func() error { return wantErr })
+	if err := group.Wait(); !errors.Is(err, wantErr) {
+		t.Fatalf("Wait() = %v, want %v", err, wantErr)
+	}
+}
+
+func TestBoundedGroupCancelsContextOnError(t *testing.T) {
+	group, groupCtx := newBoundedGroup(context.Background(), 1)
+	group.Go(func() error { return errors.New("boom") })
+	_ = group.Wait()
+	select {
+	case <-groupCtx.Done():
+	default:
+		t.Fatal("group context was not canceled after an error")
+	}
+}
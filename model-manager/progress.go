@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pullProgress aggregates the streaming status of concurrently in-flight
+// pulls so they can be reported as a single periodic log line instead of
+// interleaving raw status lines from every worker.
+type pullProgress struct {
+	mu    sync.Mutex
+	state map[string]*modelProgress
+}
+
+type modelProgress struct {
+	status    string
+	completed int64
+	total     int64
+}
+
+func newPullProgress() *pullProgress {
+	return &pullProgress{state: make(map[string]*modelProgress)}
+}
+
+func (p *pullProgress) update(model string, ev pullEvent) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	mp, ok := p.state[model]
+	if !ok {
+		mp = &modelProgress{}
+		p.state[model] = mp
+	}
+	if ev.Status != "" {
+		mp.status = ev.Status
+	}
+	if ev.Total > 0 {
+		mp.total = ev.Total
+	}
+	if ev.Completed > 0 {
+		mp.completed = ev.Completed
+	}
+}
+
+// clear removes a model from the aggregated view once its pull has
+// finished, successfully or not.
+func (p *pullProgress) clear(model string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.state, model)
+}
+
+// line renders the current state of all in-flight pulls, e.g.
+// "model X: 45% (1.2GB/2.7GB), model Y: 12%".
+func (p *pullProgress) line() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.state) == 0 {
+		return ""
+	}
+	models := make([]string, 0, len(p.state))
+	for m := range p.state {
+		models = append(models, m)
+	}
+	sort.Strings(models)
+
+	parts := make([]string, 0, len(models))
+	for _, m := range models {
+		mp := p.state[m]
+		if mp.total > 0 {
+			pct := float64(mp.completed) / float64(mp.total) * 100
+			parts = append(parts, fmt.Sprintf("%s: %.0f%% (%s/%s)", m, pct, formatBytes(mp.completed), formatBytes(mp.total)))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s: %s", m, mp.status))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// report logs an aggregated progress line on the given interval until ctx
+// is done or the returned stop func is called.
+func (p *pullProgress) report(ctx context.Context, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-ticker.C:
+				if line := p.line(); line != "" {
+					log.Printf("pull progress: %s", line)
+				}
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for n/div >= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestFormatBytes(t *testing.T) {
+	cases := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0B"},
+		{512, "512B"},
+		{1024, "1.0KiB"},
+		{1536, "1.5KiB"},
+		{1 << 30, "1.0GiB"},
+	}
+	for _, tc := range cases {
+		if got := formatBytes(tc.n); got != tc.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestPullProgressLine(t *testing.T) {
+	p := newPullProgress()
+	if got := p.line(); got != "" {
+		t.Fatalf("line() on empty progress = %q, want empty", got)
+	}
+
+	p.update("modelA", pullEvent{Status: "pulling manifest", Completed: 50, Total: 100})
+	p.update("modelB", pullEvent{Status: "downloading"})
+
+	got := p.line()
+	want := "modelA: 50% (50B/100B), modelB: downloading"
+	if got != want {
+		t.Fatalf("line() = %q, want %q", got, want)
+	}
+
+	p.clear("modelA")
+	got = p.line()
+	want = "modelB: downloading"
+	if got != want {
+		t.Fatalf("line() after clear = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// vllmBackend talks to a vLLM OpenAI-compatible server: /health for
+// liveness and /v1/models for the loaded model list. Most vLLM
+// deployments fix their model at startup, but some expose a /v1/load
+// endpoint for dynamic loading; Pull uses it where available and
+// degrades to ErrPullUnsupported otherwise.
+type vllmBackend struct {
+	base string
+}
+
+func (b *vllmBackend) Ping(ctx context.Context, timeout time.Duration) error {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, b.base+"/health", nil)
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("vllm /health status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (b *vllmBackend) List(ctx context.Context, timeout time.Duration) (map[string]struct{}, error) {
+	return listOpenAIModels(ctx, b.base, timeout)
+}
+
+func (b *vllmBackend) Pull(ctx context.Context, timeout time.Duration, model string, metrics *Metrics, progress *pullProgress) error {
+	metrics.IncPullAttempt(model)
+	body := strings.NewReader(`{"model":"` + model + `"}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/v1/load", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		metrics.IncPullFailure(model)
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("vllm: %w", ErrPullUnsupported)
+	}
+	if resp.StatusCode/100 != 2 {
+		metrics.IncPullFailure(model)
+		return fmt.Errorf("load %s failed: status %d", model, resp.StatusCode)
+	}
+	metrics.SetModelPresent(model, true)
+	return nil
+}
+
+func (b *vllmBackend) Show(ctx context.Context, timeout time.Duration, model string) (string, error) {
+	return "", fmt.Errorf("vllm: digest verification not supported")
+}
+
+// Warmup issues a single-token OpenAI-compatible completion so vLLM loads
+// model's weights onto the GPU before the first real request.
+func (b *vllmBackend) Warmup(ctx context.Context, timeout time.Duration, model string) error {
+	body := strings.NewReader(`{"model":"` + model + `","prompt":"ok","max_tokens":1}`)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, b.base+"/v1/completions", body)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient(timeout).Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 8192))
+		return fmt.Errorf("completion failed: status %d: %s", resp.StatusCode, string(respBody))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
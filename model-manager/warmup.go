@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// warmupAll calls backend.Warmup for every required model in order,
+// timing each call and recording it via metrics.RecordWarmupLatency.
+func warmupAll(ctx context.Context, backend Backend, timeout time.Duration, required []modelRef, metrics *Metrics) (map[string]time.Duration, error) {
+	latencies := make(map[string]time.Duration, len(required))
+	for _, ref := range required {
+		start := time.Now()
+		if err := backend.Warmup(ctx, timeout, ref.Name); err != nil {
+			return latencies, fmt.Errorf("warmup %s: %w", ref.Name, err)
+		}
+		latency := time.Since(start)
+		latencies[ref.Name] = latency
+		metrics.RecordWarmupLatency(ref.Name, latency)
+		log.Printf("warmup %s: %s", ref.Name, latency)
+	}
+	return latencies, nil
+}
+
+// formatLatencies renders per-model warmup latencies in required order,
+// e.g. "modelX: 1.2s, modelY: 820ms".
+func formatLatencies(required []modelRef, latencies map[string]time.Duration) string {
+	parts := make([]string, 0, len(required))
+	for _, ref := range required {
+		if lat, ok := latencies[ref.Name]; ok {
+			parts = append(parts, fmt.Sprintf("%s: %s", ref.Name, lat))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatLatencies(t *testing.T) {
+	required := []modelRef{{Name: "modelA"}, {Name: "modelB"}, {Name: "modelC"}}
+	latencies := map[string]time.Duration{
+		"modelA": 1200 * time.Millisecond,
+		"modelC": 820 * time.Millisecond,
+	}
+	got := formatLatencies(required, latencies)
+	want := "modelA: 1.2s, modelC: 820ms"
+	if got != want {
+		t.Fatalf("formatLatencies() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatLatenciesEmpty(t *testing.T) {
+	if got := formatLatencies(nil, nil); got != "" {
+		t.Fatalf("formatLatencies(nil, nil) = %q, want empty", got)
+	}
+}